@@ -29,29 +29,42 @@ partitioning the index across multiple nodes. When a document
 is indexed, the expression is evaluated for that document, and
 the resulting value determines which index node will contain an
 index value into the document.
+
+The include expressions are not part of the index key: they are
+not used for ordering or for matching the WHERE clause, but their
+values are stored alongside the key in the index leaf entries so
+that a covering scan can satisfy a query without a KV fetch.
+
+The with clause carries index-type-specific options, e.g. for
+USING VECTOR it holds the distance metric, dimensionality and
+HNSW construction parameters.
 */
 type CreateIndex struct {
 	name      string                 `json:"name"`
 	keyspace  *KeyspaceRef           `json:"keyspace"`
 	exprs     expression.Expressions `json:"expressions"`
+	include   expression.Expressions `json:"include"`
 	partition expression.Expression  `json:"partition"`
 	where     expression.Expression  `json:"where"`
 	using     datastore.IndexType    `json:"using"`
+	with      value.Value            `json:"with"`
 }
 
 /*
 The function NewCreateIndex returns a pointer to the
 CreateIndex struct with the input argument values as fields.
 */
-func NewCreateIndex(name string, keyspace *KeyspaceRef, exprs expression.Expressions,
-	partition, where expression.Expression, using datastore.IndexType) *CreateIndex {
+func NewCreateIndex(name string, keyspace *KeyspaceRef, exprs, include expression.Expressions,
+	partition, where expression.Expression, using datastore.IndexType, with value.Value) *CreateIndex {
 	return &CreateIndex{
 		name:      name,
 		keyspace:  keyspace,
 		exprs:     exprs,
+		include:   include,
 		partition: partition,
 		where:     where,
 		using:     using,
+		with:      with,
 	}
 }
 
@@ -88,6 +101,13 @@ func (this *CreateIndex) MapExpressions(mapper expression.Mapper) (err error) {
 		return
 	}
 
+	if this.include != nil {
+		err = this.include.MapExpressions(mapper)
+		if err != nil {
+			return
+		}
+	}
+
 	if this.partition != nil {
 		this.partition, err = mapper.Map(this.partition)
 		if err != nil {
@@ -126,6 +146,15 @@ func (this *CreateIndex) Keyspace() *KeyspaceRef {
 	return this.keyspace
 }
 
+/*
+Returns the include expressions of the create index statement, i.e.
+the non-key expressions whose values are stored in the index but
+that do not participate in the index key or order.
+*/
+func (this *CreateIndex) Include() expression.Expressions {
+	return this.include
+}
+
 /*
 Returns the Partition expression of the create index statement.
 */
@@ -147,6 +176,15 @@ func (this *CreateIndex) Using() datastore.IndexType {
 	return this.using
 }
 
+/*
+Returns the with clause of the create index statement, carrying
+index-type-specific options (e.g. vector index metric, dimension
+and HNSW parameters).
+*/
+func (this *CreateIndex) With() value.Value {
+	return this.with
+}
+
 /*
 Marshals input receiver into byte array.
 */
@@ -154,6 +192,13 @@ func (this *CreateIndex) MarshalJSON() ([]byte, error) {
 	r := map[string]interface{}{"type": "createIndex"}
 	r["keyspaceRef"] = this.keyspace
 	r["name"] = this.name
+	if len(this.include) > 0 {
+		includes := make([]string, len(this.include))
+		for i, expr := range this.include {
+			includes[i] = expression.NewStringer().Visit(expr)
+		}
+		r["include"] = includes
+	}
 	if this.partition != nil {
 		r["partition"] = expression.NewStringer().Visit(this.partition)
 	}
@@ -161,5 +206,8 @@ func (this *CreateIndex) MarshalJSON() ([]byte, error) {
 	if this.where != nil {
 		r["where"] = expression.NewStringer().Visit(this.where)
 	}
+	if this.with != nil {
+		r["with"] = this.with
+	}
 	return json.Marshal(r)
 }
@@ -0,0 +1,15 @@
+//  Copyright (c) 2014 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package datastore
+
+// VECTOR identifies a vector (ANN) index, searched via a KNN scan rather
+// than by key range. IndexType and the other IndexType values (DEFAULT,
+// GSI, ...) are declared alongside the rest of the datastore package.
+const VECTOR IndexType = "vector"
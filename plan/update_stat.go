@@ -53,6 +53,63 @@ func (this *UpdateStatistics) MarshalJSON() ([]byte, error) {
 	return json.Marshal(this.MarshalBase(nil))
 }
 
+// updateStatisticsWith holds the typed, round-trippable form of the
+// UpdateStatistics WITH clause: sampling controls for the equi-depth
+// histogram, plus the size of the most-common-value list tracked
+// alongside it.
+type updateStatisticsWith struct {
+	SampleSize    int64   `json:"sample_size,omitempty"`
+	SamplePercent float64 `json:"sample_percent,omitempty"`
+	Resolution    int64   `json:"resolution,omitempty"`
+	MCVSize       int64   `json:"mcv_size,omitempty"`
+}
+
+// _DEFAULT_STATS_RESOLUTION is the number of equi-depth histogram
+// buckets used when the WITH clause does not specify one.
+const _DEFAULT_STATS_RESOLUTION = 256
+
+func newUpdateStatisticsWith(with value.Value) *updateStatisticsWith {
+	if with == nil {
+		return nil
+	}
+
+	w := &updateStatisticsWith{Resolution: _DEFAULT_STATS_RESOLUTION}
+	if v, ok := with.Field("sample_size"); ok {
+		w.SampleSize = int64(v.Actual().(float64))
+	}
+	if v, ok := with.Field("sample_percent"); ok {
+		w.SamplePercent = v.Actual().(float64)
+	}
+	if v, ok := with.Field("resolution"); ok {
+		w.Resolution = int64(v.Actual().(float64))
+	}
+	if v, ok := with.Field("mcv_size"); ok {
+		w.MCVSize = int64(v.Actual().(float64))
+	}
+	return w
+}
+
+func (this *updateStatisticsWith) value() value.Value {
+	if this == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{}, 4)
+	if this.SampleSize > 0 {
+		m["sample_size"] = this.SampleSize
+	}
+	if this.SamplePercent > 0 {
+		m["sample_percent"] = this.SamplePercent
+	}
+	if this.Resolution > 0 {
+		m["resolution"] = this.Resolution
+	}
+	if this.MCVSize > 0 {
+		m["mcv_size"] = this.MCVSize
+	}
+	return value.NewValue(m)
+}
+
 func (this *UpdateStatistics) MarshalBase(f func(map[string]interface{})) map[string]interface{} {
 	r := map[string]interface{}{"#operator": "UpdateStatistics"}
 	this.node.Keyspace().MarshalKeyspace(r)
@@ -62,8 +119,8 @@ func (this *UpdateStatistics) MarshalBase(f func(map[string]interface{})) map[st
 		terms = append(terms, expression.NewStringer().Visit(term))
 	}
 	r["terms"] = terms
-	if this.node.With() != nil {
-		r["with"] = this.node.With()
+	if w := newUpdateStatisticsWith(this.node.With()); w != nil {
+		r["with"] = w
 	}
 
 	if f != nil {
@@ -74,13 +131,13 @@ func (this *UpdateStatistics) MarshalBase(f func(map[string]interface{})) map[st
 
 func (this *UpdateStatistics) UnmarshalJSON(body []byte) error {
 	var _unmarshalled struct {
-		_         string          `json:"#operator"`
-		Namespace string          `json:"namespace"`
-		Bucket    string          `json:"bucket"`
-		Scope     string          `json:"scope"`
-		Keyspace  string          `json:"keyspace"`
-		Terms     []string        `json:"terms"`
-		With      json.RawMessage `json:"with"`
+		_         string                `json:"#operator"`
+		Namespace string                `json:"namespace"`
+		Bucket    string                `json:"bucket"`
+		Scope     string                `json:"scope"`
+		Keyspace  string                `json:"keyspace"`
+		Terms     []string              `json:"terms"`
+		With      *updateStatisticsWith `json:"with"`
 	}
 
 	err := json.Unmarshal(body, &_unmarshalled)
@@ -107,12 +164,7 @@ func (this *UpdateStatistics) UnmarshalJSON(body []byte) error {
 		terms[i] = expr
 	}
 
-	var with value.Value
-	if len(_unmarshalled.With) > 0 {
-		with = value.NewValue([]byte(_unmarshalled.With))
-	}
-
-	this.node = algebra.NewUpdateStatistics(ksref, terms, with)
+	this.node = algebra.NewUpdateStatistics(ksref, terms, _unmarshalled.With.value())
 	return nil
 }
 
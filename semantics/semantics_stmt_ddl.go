@@ -13,6 +13,8 @@ import (
 	"github.com/couchbase/query/algebra"
 	"github.com/couchbase/query/datastore"
 	"github.com/couchbase/query/errors"
+	"github.com/couchbase/query/expression"
+	"github.com/couchbase/query/value"
 )
 
 func (this *SemChecker) VisitCreatePrimaryIndex(stmt *algebra.CreatePrimaryIndex) (interface{}, error) {
@@ -26,9 +28,94 @@ func (this *SemChecker) VisitCreateIndex(stmt *algebra.CreateIndex) (interface{}
 			return nil, errors.NewSemanticsError(nil, "MISSING attribute only allowed on GSI index leading key")
 		}
 	}
+
+	if len(stmt.Include()) > 0 {
+		keys := make(map[string]bool, len(stmt.Expressions()))
+		for _, expr := range stmt.Expressions() {
+			keys[expression.NewStringer().Visit(expr)] = true
+		}
+
+		seen := make(map[string]bool, len(stmt.Include()))
+		for _, expr := range stmt.Include() {
+			str := expression.NewStringer().Visit(expr)
+			if keys[str] {
+				return nil, errors.NewSemanticsError(nil, "Expression in INCLUDE clause must not be an index key: "+str)
+			}
+			if seen[str] {
+				return nil, errors.NewSemanticsError(nil, "Duplicate expression in INCLUDE clause: "+str)
+			}
+			seen[str] = true
+		}
+	}
+
+	if stmt.Using() == datastore.VECTOR {
+		if len(stmt.Expressions()) != 1 {
+			return nil, errors.NewSemanticsError(nil, "USING VECTOR requires exactly one index key expression")
+		}
+		if err := validateVectorWith(stmt.With()); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, stmt.MapExpressions(this)
 }
 
+var _VECTOR_METRICS = map[string]bool{
+	"cosine": true,
+	"l2":     true,
+	"dot":    true,
+}
+
+/*
+Validates the WITH clause of a USING VECTOR index: metric must be one
+of cosine, l2 or dot, dim must be a positive integer, and the optional
+HNSW construction parameters m and efConstruction, if present, must
+also be positive integers.
+*/
+func validateVectorWith(with value.Value) error {
+	if with == nil {
+		return errors.NewSemanticsError(nil, "USING VECTOR requires a WITH clause specifying metric and dim")
+	}
+
+	metric, ok := with.Field("metric")
+	if !ok || metric.Type() != value.STRING || !_VECTOR_METRICS[metric.Actual().(string)] {
+		return errors.NewSemanticsError(nil, "USING VECTOR WITH clause requires metric to be one of cosine, l2, dot")
+	}
+
+	if err := validatePositiveIntWith(with, "dim", true); err != nil {
+		return err
+	}
+	if err := validatePositiveIntWith(with, "m", false); err != nil {
+		return err
+	}
+	if err := validatePositiveIntWith(with, "efConstruction", false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validatePositiveIntWith(with value.Value, field string, required bool) error {
+	v, ok := with.Field(field)
+	if !ok {
+		if required {
+			return errors.NewSemanticsError(nil, "USING VECTOR WITH clause requires a positive integer "+field)
+		}
+		return nil
+	}
+
+	if v.Type() != value.NUMBER {
+		return errors.NewSemanticsError(nil, "USING VECTOR WITH clause requires "+field+" to be a positive integer")
+	}
+
+	f := v.Actual().(float64)
+	if f <= 0 || f != float64(int64(f)) {
+		return errors.NewSemanticsError(nil, "USING VECTOR WITH clause requires "+field+" to be a positive integer")
+	}
+
+	return nil
+}
+
 func (this *SemChecker) VisitDropIndex(stmt *algebra.DropIndex) (interface{}, error) {
 	return nil, stmt.MapExpressions(this)
 }
@@ -41,6 +128,65 @@ func (this *SemChecker) VisitBuildIndexes(stmt *algebra.BuildIndexes) (interface
 	return nil, stmt.MapExpressions(this)
 }
 
+func (this *SemChecker) VisitUpdateStatistics(stmt *algebra.UpdateStatistics) (interface{}, error) {
+	if err := validateUpdateStatisticsWith(stmt.With()); err != nil {
+		return nil, err
+	}
+	return nil, stmt.MapExpressions(this)
+}
+
+func isInteger(f float64) bool {
+	return f == float64(int64(f))
+}
+
+func isPositiveInteger(f float64) bool {
+	return f > 0 && isInteger(f)
+}
+
+/*
+Validates the WITH clause of an UPDATE STATISTICS statement: sample_size
+and sample_percent are mutually exclusive, sample_percent must be in
+(0, 100], resolution (the equi-depth bucket count) must be at least 2,
+and mcv_size, if given, must be a non-negative integer.
+*/
+func validateUpdateStatisticsWith(with value.Value) error {
+	if with == nil {
+		return nil
+	}
+
+	_, hasSize := with.Field("sample_size")
+	_, hasPercent := with.Field("sample_percent")
+	if hasSize && hasPercent {
+		return errors.NewSemanticsError(nil, "UPDATE STATISTICS WITH clause cannot specify both sample_size and sample_percent")
+	}
+
+	if v, ok := with.Field("sample_size"); ok {
+		if v.Type() != value.NUMBER || !isPositiveInteger(v.Actual().(float64)) {
+			return errors.NewSemanticsError(nil, "UPDATE STATISTICS WITH clause requires sample_size to be a positive integer")
+		}
+	}
+
+	if v, ok := with.Field("sample_percent"); ok {
+		if v.Type() != value.NUMBER || v.Actual().(float64) <= 0 || v.Actual().(float64) > 100 {
+			return errors.NewSemanticsError(nil, "UPDATE STATISTICS WITH clause requires sample_percent to be in (0, 100]")
+		}
+	}
+
+	if v, ok := with.Field("resolution"); ok {
+		if v.Type() != value.NUMBER || !isInteger(v.Actual().(float64)) || v.Actual().(float64) < 2 {
+			return errors.NewSemanticsError(nil, "UPDATE STATISTICS WITH clause requires resolution to be an integer of at least 2")
+		}
+	}
+
+	if v, ok := with.Field("mcv_size"); ok {
+		if v.Type() != value.NUMBER || !isInteger(v.Actual().(float64)) || v.Actual().(float64) < 0 {
+			return errors.NewSemanticsError(nil, "UPDATE STATISTICS WITH clause requires mcv_size to be a non-negative integer")
+		}
+	}
+
+	return nil
+}
+
 func (this *SemChecker) VisitCreateScope(stmt *algebra.CreateScope) (interface{}, error) {
 	return nil, stmt.MapExpressions(this)
 }
@@ -64,17 +64,73 @@ const _MIN_BUCKETS = 8
 const _MAX_BUCKETS = 64
 const _POOL_SIZE = 1024
 
+// _RESIZE_WINDOW is the number of Gets sampled before the adaptive sizer
+// re-evaluates the miss rate and occupancy of a pool.
+const _RESIZE_WINDOW = 2048
+
+// _DEFAULT_TARGET_MISS_RATE is the Get-miss rate, above which NewFastPool
+// (as opposed to NewFastPoolWithLimits) grows a pool's bucket count.
+const _DEFAULT_TARGET_MISS_RATE = 0.1
+
+// _LOW_OCCUPANCY is the useCount/cap ratio, below which a pool is
+// considered under-used and its bucket count is shrunk back down.
+const _LOW_OCCUPANCY = 0.25
+
+// FastPool is a lock-free (on the fast path) object pool sharded across a
+// number of buckets to reduce contention. The bucket count starts out
+// sized to runtime.NumCPU() and then adapts: a high Get-miss rate grows it
+// (up to a configurable maximum) to spread load further, while sustained
+// low occupancy shrinks it back down (to a configurable minimum) to avoid
+// holding onto unused capacity. Resizing swaps in a fresh fastPoolState
+// under a rare writer lock; only the Get that closes a sampling window
+// takes that lock, other Gets and Puts never block on it.
 type FastPool struct {
-	getNext   uint32
-	putNext   uint32
+	getNext uint32
+	putNext uint32
+	f       func() interface{}
+
+	state unsafe.Pointer // *fastPoolState, read with atomic.LoadPointer on the fast path
+
+	minBuckets    uint32
+	maxBuckets    uint32
+	perBucketCap  int32  // capacity quota per bucket; cap = buckets * perBucketCap
+	missRateX1000 uint32 // target miss rate * 1000, since we have no atomic float64
+
+	gets   int64
+	puts   int64
+	misses int64
+
+	windowGets   int32
+	windowMisses int32
+	resizeMu     sync.Mutex
+}
+
+// fastPoolState holds everything a resize needs to replace as one unit:
+// the bucket slices and the useCount/freeCount bookkeeping and capacity
+// that apply to them. Counting useCount/freeCount here, rather than on
+// FastPool itself, means a resize is a single atomic pointer swap - there
+// is no window in which a Get or Put that already loaded the old state
+// can bump a counter after it was reset but before the new buckets are
+// visible, because the counter it bumps lives with the buckets it used.
+type fastPoolState struct {
+	buckets   uint32
+	cap       int32
 	useCount  int32
 	freeCount int32
-	buckets   uint32
-	f         func() interface{}
 	pool      []poolList
 	free      []poolList
 }
 
+// FastPoolStats is a snapshot of a FastPool's usage, suitable for
+// reporting through an admin/stats endpoint.
+type FastPoolStats struct {
+	Gets    int64
+	Puts    int64
+	Misses  int64
+	Cap     int32
+	Buckets uint32
+}
+
 type poolList struct {
 	head *poolEntry
 	tail *poolEntry
@@ -87,51 +143,164 @@ type poolEntry struct {
 }
 
 func NewFastPool(p *FastPool, f func() interface{}) {
+	NewFastPoolWithLimits(p, f, _MIN_BUCKETS, _MAX_BUCKETS, _DEFAULT_TARGET_MISS_RATE)
+}
+
+// NewFastPoolWithLimits is NewFastPool with the bucket count bounds and
+// the target Get-miss rate that drives growth made explicit, instead of
+// defaulting to _MIN_BUCKETS/_MAX_BUCKETS/_DEFAULT_TARGET_MISS_RATE.
+func NewFastPoolWithLimits(p *FastPool, f func() interface{}, min, max int, targetMissRate float64) {
 	*p = FastPool{}
-	p.buckets = uint32(runtime.NumCPU())
-	if p.buckets > _MAX_BUCKETS {
-		p.buckets = _MAX_BUCKETS
-	} else if p.buckets < _MIN_BUCKETS {
-		p.buckets = _MIN_BUCKETS
-	}
-	p.pool = make([]poolList, p.buckets)
-	p.free = make([]poolList, p.buckets)
+	buckets := uint32(runtime.NumCPU())
+	if buckets > uint32(max) {
+		buckets = uint32(max)
+	} else if buckets < uint32(min) {
+		buckets = uint32(min)
+	}
+	p.minBuckets = uint32(min)
+	p.maxBuckets = uint32(max)
+	// Gives a capacity of _POOL_SIZE at the minimum bucket count (the
+	// legacy _POOL_SIZE at _MIN_BUCKETS buckets for NewFastPool),
+	// scaling linearly with the bucket count as it grows or shrinks.
+	p.perBucketCap = _POOL_SIZE / int32(min)
+	p.missRateX1000 = uint32(targetMissRate * 1000)
 	p.f = f
+	p.setState(&fastPoolState{
+		buckets: buckets,
+		cap:     int32(buckets) * p.perBucketCap,
+		pool:    make([]poolList, buckets),
+		free:    make([]poolList, buckets),
+	})
+}
+
+func (p *FastPool) loadState() *fastPoolState {
+	return (*fastPoolState)(atomic.LoadPointer(&p.state))
+}
+
+func (p *FastPool) setState(s *fastPoolState) {
+	atomic.StorePointer(&p.state, unsafe.Pointer(s))
 }
 
 func (p *FastPool) Get() interface{} {
-	if atomic.LoadInt32(&p.useCount) == 0 {
+	st := p.loadState()
+	atomic.AddInt64(&p.gets, 1)
+	if atomic.LoadInt32(&st.useCount) == 0 {
+		p.sample(st, false)
+		atomic.AddInt64(&p.misses, 1)
 		return p.f()
 	}
-	l := atomic.AddUint32(&p.getNext, 1) % p.buckets
-	e := p.pool[l].Get()
+	l := atomic.AddUint32(&p.getNext, 1) % st.buckets
+	e := st.pool[l].Get()
 	if e == nil {
+		p.sample(st, false)
+		atomic.AddInt64(&p.misses, 1)
 		return p.f()
 	}
-	atomic.AddInt32(&p.useCount, -1)
+	p.sample(st, true)
+	atomic.AddInt32(&st.useCount, -1)
 	rv := e.entry
 	e.entry = nil
-	if atomic.LoadInt32(&p.freeCount) < _POOL_SIZE {
-		atomic.AddInt32(&p.freeCount, 1)
-		p.free[l].Put(e)
+	if atomic.LoadInt32(&st.freeCount) < st.cap {
+		atomic.AddInt32(&st.freeCount, 1)
+		st.free[l].Put(e)
 	}
 	return rv
 }
 
 func (p *FastPool) Put(s interface{}) {
-	if atomic.LoadInt32(&p.useCount) >= _POOL_SIZE {
+	st := p.loadState()
+	atomic.AddInt64(&p.puts, 1)
+	if atomic.LoadInt32(&st.useCount) >= st.cap {
 		return
 	}
-	l := atomic.AddUint32(&p.putNext, 1) % p.buckets
-	e := p.free[l].Get()
+	l := atomic.AddUint32(&p.putNext, 1) % st.buckets
+	e := st.free[l].Get()
 	if e == nil {
 		e = &poolEntry{}
 	} else {
-		atomic.AddInt32(&p.freeCount, -1)
+		atomic.AddInt32(&st.freeCount, -1)
 	}
 	e.entry = s
-	p.pool[l].Put(e)
-	atomic.AddInt32(&p.useCount, 1)
+	st.pool[l].Put(e)
+	atomic.AddInt32(&st.useCount, 1)
+}
+
+// Stats returns a snapshot of this pool's usage and current sizing.
+func (p *FastPool) Stats() FastPoolStats {
+	st := p.loadState()
+	return FastPoolStats{
+		Gets:    atomic.LoadInt64(&p.gets),
+		Puts:    atomic.LoadInt64(&p.puts),
+		Misses:  atomic.LoadInt64(&p.misses),
+		Cap:     st.cap,
+		Buckets: st.buckets,
+	}
+}
+
+// sample is the cheap, lock-free per-Get counter check that decides
+// whether a window's worth of Gets has been observed; only the goroutine
+// that wins the window-reset CAS evaluates a resize, so this never adds
+// contention to the common path. st is the state the just-completed Get
+// ran against, so occupancy is read against the same cap it was gated by.
+func (p *FastPool) sample(st *fastPoolState, hit bool) {
+	if !hit {
+		atomic.AddInt32(&p.windowMisses, 1)
+	}
+	gets := atomic.AddInt32(&p.windowGets, 1)
+	if gets < _RESIZE_WINDOW {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&p.windowGets, gets, 0) {
+		return
+	}
+	misses := atomic.SwapInt32(&p.windowMisses, 0)
+
+	missRate := float64(misses) / float64(gets)
+	target := float64(atomic.LoadUint32(&p.missRateX1000)) / 1000
+	occupancy := float64(atomic.LoadInt32(&st.useCount)) / float64(st.cap)
+
+	if missRate > target {
+		p.resize(true)
+	} else if occupancy < _LOW_OCCUPANCY {
+		p.resize(false)
+	}
+}
+
+// resize grows or shrinks the bucket count and capacity, swapping in a
+// freshly allocated fastPoolState as a single atomic pointer store. It
+// takes a plain mutex rather than trying to be lock-free: it only ever
+// runs once per _RESIZE_WINDOW Gets, so it is never on the hot path.
+// Because useCount/freeCount/cap live on the state being replaced, the
+// swap can never orphan bookkeeping the way resetting shared counters
+// out-of-band could: a Get/Put already holding the old state only ever
+// touches the old state's own counts.
+func (p *FastPool) resize(grow bool) {
+	p.resizeMu.Lock()
+	defer p.resizeMu.Unlock()
+
+	st := p.loadState()
+	buckets := st.buckets
+	if grow {
+		buckets *= 2
+		if buckets > p.maxBuckets {
+			buckets = p.maxBuckets
+		}
+	} else {
+		buckets /= 2
+		if buckets < p.minBuckets {
+			buckets = p.minBuckets
+		}
+	}
+	if buckets == st.buckets {
+		return
+	}
+
+	p.setState(&fastPoolState{
+		buckets: buckets,
+		cap:     int32(buckets) * p.perBucketCap,
+		pool:    make([]poolList, buckets),
+		free:    make([]poolList, buckets),
+	})
 }
 
 func (l *poolList) Get() *poolEntry {
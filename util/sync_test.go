@@ -0,0 +1,97 @@
+//  Copyright (c) 2024 Couchbase, Inc.
+//  Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+//  except in compliance with the License. You may obtain a copy of the License at
+//    http://www.apache.org/licenses/LICENSE-2.0
+//  Unless required by applicable law or agreed to in writing, software distributed under the
+//  License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+//  either express or implied. See the License for the specific language governing permissions
+//  and limitations under the License.
+
+package util
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestFastPoolConcurrentResize drives Get/Put from several goroutines while
+// resize() runs concurrently, so that `go test -race` can catch any data
+// race in the atomic state swap.
+func TestFastPoolConcurrentResize(t *testing.T) {
+	var p FastPool
+	NewFastPoolWithLimits(&p, func() interface{} { return new(int) }, 2, 16, 0.5)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				p.Put(p.Get())
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		p.resize(i%2 == 0)
+	}
+
+	close(stop)
+	wg.Wait()
+
+	st := p.Stats()
+	if st.Buckets < 2 || st.Buckets > 16 {
+		t.Fatalf("bucket count %d out of configured [2,16] range", st.Buckets)
+	}
+	if st.Gets == 0 || st.Puts == 0 {
+		t.Fatalf("expected Get/Put activity to be recorded, got gets=%d puts=%d", st.Gets, st.Puts)
+	}
+}
+
+// TestFastPoolResizeDoesNotOrphanCounts guards against useCount/freeCount
+// outliving the pool/free buckets they were counted against: since both
+// live on fastPoolState, a resize's single pointer swap must present a
+// brand-new state with its own zeroed counts, while leaving whatever a
+// goroutine still holding the old state sees untouched.
+func TestFastPoolResizeDoesNotOrphanCounts(t *testing.T) {
+	var p FastPool
+	NewFastPoolWithLimits(&p, func() interface{} { return new(int) }, 4, 8, 0.5)
+	oldSt := &fastPoolState{buckets: 4, cap: 100, pool: make([]poolList, 4), free: make([]poolList, 4)}
+	p.setState(oldSt)
+
+	for i := 0; i < 10; i++ {
+		p.Put(new(int))
+	}
+	if oldSt.useCount == 0 {
+		t.Fatalf("expected oldSt.useCount to be non-zero after Puts")
+	}
+
+	p.resize(true)
+
+	newSt := p.loadState()
+	if newSt == oldSt {
+		t.Fatalf("resize did not install a new state")
+	}
+	if newSt.buckets != 8 {
+		t.Fatalf("buckets = %d after resize(true), want 8", newSt.buckets)
+	}
+	if newSt.useCount != 0 {
+		t.Fatalf("useCount = %d on new state, want 0", newSt.useCount)
+	}
+	if newSt.freeCount != 0 {
+		t.Fatalf("freeCount = %d on new state, want 0", newSt.freeCount)
+	}
+	// The old state, still referenced by oldSt, must be untouched: nothing
+	// resets it out-of-band from underneath a goroutine that is mid-Get/Put
+	// against it.
+	if oldSt.useCount == 0 {
+		t.Fatalf("old state's useCount was mutated by resize, want it left as-is")
+	}
+}